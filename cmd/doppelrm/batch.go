@@ -0,0 +1,98 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/blackhawk42/doppelrm/pkg/deleter"
+	"github.com/blackhawk42/doppelrm/pkg/doppelparser"
+	"github.com/blackhawk42/doppelrm/pkg/policy"
+)
+
+// buildPolicy assembles the policy.Policy selected through cmd's flags.
+func buildPolicy(cmd *RmCmd) (policy.Policy, error) {
+	return policy.New(cmd.Policy, cmd.KeepInDir, cmd.Prefer)
+}
+
+// candidatesFor turns a collision's filenames into policy
+// candidates, stat-ing each one for its modification time. A file that
+// can't be stat-ed is kept as a candidate, so it's still visible in the
+// plan, but marked !Exists so a ModTime-ranking policy can't be fooled by
+// its zero ModTime into keeping it over a file that actually exists.
+func candidatesFor(filenames []string) []policy.Candidate {
+	candidates := make([]policy.Candidate, 0, len(filenames))
+	for _, name := range filenames {
+		var modTime time.Time
+		info, err := os.Stat(name)
+		exists := err == nil
+		if exists {
+			modTime = info.ModTime()
+		}
+
+		candidates = append(candidates, policy.Candidate{Path: name, ModTime: modTime, Exists: exists})
+	}
+
+	return candidates
+}
+
+// plannedRemoval is one collision's resolution, in the shape printed by
+// --dry-run.
+type plannedRemoval struct {
+	Hash   string   `json:"hash"`
+	Keep   string   `json:"keep"`
+	Remove []string `json:"remove"`
+}
+
+// runNonInteractive resolves every collision in dc through p without
+// starting the TUI, either removing the losing files through backend or,
+// with dryRun, printing the plan as JSON.
+func runNonInteractive(dc *doppelparser.DoppelCollisions, p policy.Policy, backend deleter.Backend, journal *deleter.Journal, dryRun bool) error {
+	var plan []plannedRemoval
+
+	for hash, filenames := range dc.Iter() {
+		keep, remove, err := p.Resolve(candidatesFor(filenames))
+		if err != nil {
+			return fmt.Errorf("while resolving collision %s: %w", hash, err)
+		}
+
+		removePaths := make([]string, 0, len(remove))
+		for _, c := range remove {
+			removePaths = append(removePaths, c.Path)
+		}
+
+		if dryRun {
+			plan = append(plan, plannedRemoval{Hash: hash, Keep: keep[0].Path, Remove: removePaths})
+			continue
+		}
+
+		for _, c := range remove {
+			trashLocation, err := backend.Remove(c.Path, keep[0].Path)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "error while removing %s: %v\n", c.Path, err)
+				continue
+			}
+
+			if err := journal.Record(deleter.JournalEntry{
+				Hash:          hash,
+				OriginalPath:  c.Path,
+				Backend:       backend.Name(),
+				Timestamp:     time.Now(),
+				TrashLocation: trashLocation,
+			}); err != nil {
+				fmt.Fprintf(os.Stderr, "error while recording undo journal for %s: %v\n", c.Path, err)
+			}
+
+			fmt.Fprintf(os.Stderr, "removed %s\n", c.Path)
+		}
+	}
+
+	if dryRun {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(plan)
+	}
+
+	return nil
+}
@@ -1,12 +1,17 @@
 package main
 
 import (
+	"context"
 	"errors"
 	"fmt"
+	"io"
 	"os"
 	"strings"
+	"time"
 
+	"github.com/blackhawk42/doppelrm/pkg/deleter"
 	"github.com/blackhawk42/doppelrm/pkg/doppelparser"
+	"github.com/blackhawk42/doppelrm/pkg/policy"
 	"github.com/charmbracelet/bubbles/help"
 	"github.com/charmbracelet/bubbles/key"
 	"github.com/charmbracelet/bubbles/progress"
@@ -31,6 +36,18 @@ type keyMap struct {
 	// Toggle whether the current file is selected
 	toggle key.Binding
 
+	// Pre-fill selections for the current collision using the active policy
+	autoSelect key.Binding
+
+	// Toggle the preview pane
+	previewToggle key.Binding
+
+	// Cycle which file the cursored one is diffed against
+	diffCycle key.Binding
+
+	// Toggle the list view, for jumping straight to unreviewed collisions
+	listView key.Binding
+
 	// Exit the program while confirming choices
 	enter key.Binding
 
@@ -64,6 +81,22 @@ func defaultKeyMap() *keyMap {
 			key.WithKeys(" "),
 			key.WithHelp("space", "toggle choice"),
 		),
+		autoSelect: key.NewBinding(
+			key.WithKeys("a"),
+			key.WithHelp("a", "auto-select via policy"),
+		),
+		previewToggle: key.NewBinding(
+			key.WithKeys("p"),
+			key.WithHelp("p", "toggle preview"),
+		),
+		diffCycle: key.NewBinding(
+			key.WithKeys("tab"),
+			key.WithHelp("tab", "cycle diff target"),
+		),
+		listView: key.NewBinding(
+			key.WithKeys("v"),
+			key.WithHelp("v", "jump to a collision"),
+		),
 		enter: key.NewBinding(
 			key.WithKeys("enter"),
 			key.WithHelp("↵", "confirm choices"),
@@ -80,13 +113,14 @@ func defaultKeyMap() *keyMap {
 }
 
 func (k keyMap) ShortHelp() []key.Binding {
-	return []key.Binding{k.toggle, k.quit, k.enter, k.help}
+	return []key.Binding{k.toggle, k.autoSelect, k.quit, k.enter, k.help}
 }
 
 func (k keyMap) FullHelp() [][]key.Binding {
 	return [][]key.Binding{
-		{k.toggle, k.quit, k.enter, k.help},
+		{k.toggle, k.autoSelect, k.quit, k.enter, k.help},
 		{k.up, k.down, k.left, k.right},
+		{k.previewToggle, k.diffCycle, k.listView},
 	}
 }
 
@@ -104,17 +138,29 @@ type filesChoice struct {
 }
 
 func (fc *filesChoice) Next() {
+	if len(fc.files) == 0 {
+		return
+	}
 	fc.cursor = (fc.cursor + 1) % len(fc.files)
 }
 
 func (fc *filesChoice) Prev() {
+	if len(fc.files) == 0 {
+		return
+	}
 	fc.cursor--
 	if fc.cursor < 0 {
 		fc.cursor = len(fc.files) - 1
 	}
 }
 
+// CurrentFile returns the cursored file, or nil for a collision with no
+// filenames. ParseDoppelStream never emits one, but this guards against the
+// case defensively rather than panicking if it ever did.
 func (fc *filesChoice) CurrentFile() *filename {
+	if len(fc.files) == 0 {
+		return nil
+	}
 	return fc.files[fc.cursor]
 }
 
@@ -122,6 +168,10 @@ func (fc *filesChoice) CurrentFile() *filename {
 type collision struct {
 	hash        string
 	fileChoices filesChoice
+
+	// Whether the user has moved off this collision at least once, i.e.
+	// it's no longer pending first review.
+	reviewed bool
 }
 
 // collisionChoice represents a choice between multiple collisions
@@ -145,6 +195,10 @@ func (cc *collisionChoice) CurrentCollision() *collision {
 	return cc.collisions[cc.cursor]
 }
 
+func (cc *collisionChoice) Empty() bool {
+	return len(cc.collisions) == 0
+}
+
 // utility to push a new error into finalErr, without repeats
 func pushError(err error, s []string) []string {
 	msg := err.Error()
@@ -171,9 +225,94 @@ type bblModel struct {
 	// The keybindings of the application.
 	keymap *keyMap
 
+	// The doppel file parsed so far, built up incrementally as collisions
+	// arrive; kept around to re-verify hashes on demand.
+	doppelFile *doppelparser.DoppelCollisions
+
+	// Where collisions are streamed in from, and the parse error (nil on
+	// success) sent once that channel closes.
+	collisionsCh <-chan *doppelparser.Collision
+	parseErrsCh  <-chan error
+
+	// Closed once the input has been fully streamed in.
+	closer io.Closer
+
+	// Whether more collisions may still arrive.
+	streaming bool
+
+	// Whether to re-verify file digests as the user navigates collisions.
+	verify bool
+
+	// Algorithm to assume when verifying a hash whose algorithm couldn't be
+	// determined from the report itself (no algo: header, no per-hash
+	// prefix). HashAlgoUnknown means such a hash still fails to verify.
+	verifyFallbackAlgo doppelparser.HashAlgo
+
+	// The backend used to get rid of files once choices are confirmed.
+	deleterBackend deleter.Backend
+
+	// The policy used by the auto-select keybinding.
+	policy policy.Policy
+
+	// Where deletions are recorded for later undo.
+	journal *deleter.Journal
+
+	// Whether deletion is underway, i.e. the user pressed enter.
+	deleting bool
+
+	// How many files are being deleted, and how many have finished so far.
+	deleteTotal, deleteDone int
+
+	// Status line per finished deletion, shown while deleting.
+	deleteLog []string
+
+	// Whether any deletion so far has failed; a completed run with no
+	// failures has nothing left to resume, so its session entry is cleared.
+	deleteHadError bool
+
 	// All the collisions to select from
 	collisionChoices *collisionChoice
 
+	// Whether the preview pane is shown alongside the collision list.
+	showPreview bool
+
+	// Metadata for the cursored file, filled in by previewCmd. Nil while
+	// loading or when the pane is hidden.
+	preview *filePreview
+
+	// Index, within the current collision's files, of the file the
+	// cursored one is diffed against. -1 means no target picked yet.
+	diffTarget int
+
+	// Result of diffing the cursored file against diffTarget, filled in by
+	// diffCmd.
+	diff *diffResponse
+
+	// Whether the list view, for jumping to a collision directly, is shown
+	// instead of the normal single-collision view.
+	listView bool
+
+	// Cursor within the list view.
+	listViewCursor int
+
+	// Where the session file lives. Empty disables session persistence
+	// entirely, e.g. because input is stdin.
+	sessionPath string
+
+	// Key identifying this doppel report within the session file.
+	sessionKey string
+
+	// Index of the collision to restore the cursor to once it has
+	// streamed in. -1 once there's nothing left to restore.
+	restoreCursor int
+
+	// Per-collision selections and review status loaded from a previous
+	// session, keyed by hash. Nil if there was nothing to restore.
+	restoreData map[string]collisionSession
+
+	// Whether a debounced session save is already scheduled.
+	sessionSavePending bool
+
 	// The help footer
 	help help.Model
 
@@ -205,16 +344,38 @@ type bblModel struct {
 	tempErrorsStyle lipgloss.Style
 }
 
-func newBblModel(dc *doppelparser.DoppelCollisions) bblModel {
-	m := bblModel{
-		finalErr:         nil,
-		confirmedChoices: false,
-		tempErrors:       nil,
-		keymap:           defaultKeyMap(),
+func newBblModel(collisions <-chan *doppelparser.Collision, parseErrs <-chan error, closer io.Closer, verify bool, verifyFallbackAlgo doppelparser.HashAlgo, backend deleter.Backend, journal *deleter.Journal, p policy.Policy, sessionPath string, sessionKey string, sess *sessionData) bblModel {
+	restoreCursor := -1
+	var restoreData map[string]collisionSession
+	if sess != nil {
+		restoreCursor = sess.Cursor
+		restoreData = sess.Collisions
+	}
+
+	return bblModel{
+		finalErr:           nil,
+		confirmedChoices:   false,
+		tempErrors:         nil,
+		keymap:             defaultKeyMap(),
+		doppelFile:         doppelparser.NewDoppelCollisions(),
+		collisionsCh:       collisions,
+		parseErrsCh:        parseErrs,
+		closer:             closer,
+		streaming:          true,
+		verify:             verify,
+		verifyFallbackAlgo: verifyFallbackAlgo,
+		deleterBackend:     backend,
+		journal:            journal,
+		policy:             p,
 		collisionChoices: &collisionChoice{
 			cursor:     0,
-			collisions: make([]*collision, 0, dc.Len()),
+			collisions: make([]*collision, 0),
 		},
+		sessionPath:        sessionPath,
+		sessionKey:         sessionKey,
+		restoreCursor:      restoreCursor,
+		restoreData:        restoreData,
+		diffTarget:         -1,
 		help:               help.New(),
 		widthStyle:         lipgloss.NewStyle(),
 		progress:           progress.New(progress.WithoutPercentage()),
@@ -226,34 +387,34 @@ func newBblModel(dc *doppelparser.DoppelCollisions) bblModel {
 		invalidStyle:       lipgloss.NewStyle().Foreground(lipgloss.Color("9")),
 		tempErrorsStyle:    lipgloss.NewStyle().Faint(true),
 	}
+}
 
-	for hash, cols := range dc.Iter() {
-		collisionChoice := &collision{
-			hash: hash,
-			fileChoices: filesChoice{
-				cursor: 0,
-				files:  make([]*filename, 0, len(cols)),
-			},
-		}
+func (m bblModel) Init() tea.Cmd {
+	return ReceiveCollisionCmd(m.collisionsCh, m.parseErrsCh)
+}
 
-		for _, c := range cols {
-			fn := &filename{
-				name:     c,
-				selected: true,
-				valid:    true,
-			}
+// nextCollisionMsg is a response message to a ReceiveCollisionCmd command.
+// coll is nil once the stream is exhausted, at which point err carries the
+// stream's terminal error, if any.
+type nextCollisionMsg struct {
+	coll *doppelparser.Collision
+	err  error
+}
 
-			collisionChoice.fileChoices.files = append(collisionChoice.fileChoices.files, fn)
+// ReceiveCollisionCmd waits for the next collision to arrive on collisions,
+// or for parseErrs to report the stream is done.
+//
+// Final command returns a nextCollisionMsg message, same async pattern as
+// CheckFilesExistsCmd.
+func ReceiveCollisionCmd(collisions <-chan *doppelparser.Collision, parseErrs <-chan error) tea.Cmd {
+	return func() tea.Msg {
+		coll, ok := <-collisions
+		if ok {
+			return tea.Msg(nextCollisionMsg{coll: coll})
 		}
 
-		m.collisionChoices.collisions = append(m.collisionChoices.collisions, collisionChoice)
+		return tea.Msg(nextCollisionMsg{err: <-parseErrs})
 	}
-
-	return m
-}
-
-func (m bblModel) Init() tea.Cmd {
-	return CheckFilesExistsCmd(m.collisionChoices.CurrentCollision().fileChoices.files)
 }
 
 // fileExistsResponse is a response message to a CheckFilesExistsCmd command.
@@ -293,6 +454,141 @@ func CheckFilesExistsCmd(files []*filename) tea.Cmd {
 	}
 }
 
+// hashVerifiedResponse is a response message to a VerifyCollisionCmd command.
+type hashVerifiedResponse struct {
+	// hash is the collision these results belong to.
+	hash string
+
+	// err is set if the hash could not be verified at all, e.g. because its
+	// algorithm is unknown. Per-file errors are carried in results instead.
+	err error
+
+	// results holds the outcome of re-hashing every file in the collision.
+	results []doppelparser.VerifyResult
+}
+
+// VerifyCollisionCmd is a command to re-verify every file recorded under
+// hash against its digest.
+//
+// Final command returns a hashVerifiedResponse message. Note that this will only
+// return the message, *not* modify the files. This should be handled by the
+// model Update method.
+func VerifyCollisionCmd(dc *doppelparser.DoppelCollisions, hash string, fallbackAlgo doppelparser.HashAlgo) tea.Cmd {
+	return func() tea.Msg {
+		results, err := dc.Verify(context.Background(), hash, fallbackAlgo)
+		return tea.Msg(hashVerifiedResponse{hash: hash, err: err, results: results})
+	}
+}
+
+// deleteResultMsg is a response message to a DeleteFileCmd command.
+type deleteResultMsg struct {
+	path string
+	err  error
+}
+
+// DeleteFileCmd gets rid of path through backend and, if that succeeds,
+// records the removal in journal so it can later be undone.
+//
+// Final command returns a deleteResultMsg message. This is the same
+// async-command-then-message pattern as CheckFilesExistsCmd.
+func DeleteFileCmd(backend deleter.Backend, journal *deleter.Journal, hash string, path string, keep string) tea.Cmd {
+	return func() tea.Msg {
+		trashLocation, err := backend.Remove(path, keep)
+		if err == nil {
+			err = journal.Record(deleter.JournalEntry{
+				Hash:          hash,
+				OriginalPath:  path,
+				Backend:       backend.Name(),
+				Timestamp:     time.Now(),
+				TrashLocation: trashLocation,
+			})
+		}
+
+		return tea.Msg(deleteResultMsg{path: path, err: err})
+	}
+}
+
+// autoSelectCurrentCollision pre-fills selections for the current collision
+// using m.policy, for the user to review before confirming.
+func (m bblModel) autoSelectCurrentCollision() error {
+	if m.policy == nil {
+		return fmt.Errorf("no policy configured for auto-select")
+	}
+
+	col := m.collisionChoices.CurrentCollision()
+	filenames := make([]string, 0, len(col.fileChoices.files))
+	for _, f := range col.fileChoices.files {
+		filenames = append(filenames, f.name)
+	}
+
+	keep, _, err := m.policy.Resolve(candidatesFor(filenames))
+	if err != nil {
+		return fmt.Errorf("while auto-selecting %s: %w", col.hash, err)
+	}
+
+	keepPath := keep[0].Path
+	for _, f := range col.fileChoices.files {
+		f.selected = f.name == keepPath
+	}
+
+	return nil
+}
+
+// persistSessionOnQuit best-effort saves the session synchronously, so
+// state survives even when the program quits before a debounced save fires.
+func (m *bblModel) persistSessionOnQuit() {
+	if m.sessionPath == "" {
+		return
+	}
+
+	if err := saveSession(m.sessionPath, m.sessionKey, m.buildSessionData()); err != nil {
+		m.tempErrors = pushError(err, m.tempErrors)
+	}
+}
+
+// clearSessionOnCompletion drops this run's session entry once every
+// deletion has gone through cleanly, so a later run against the same doppel
+// report starts fresh instead of restoring selections for files that are
+// already gone. Left alone if any deletion failed, since there's still
+// unresolved work to pick back up.
+func (m *bblModel) clearSessionOnCompletion() {
+	if m.sessionPath == "" || m.deleteHadError {
+		return
+	}
+
+	if err := clearSession(m.sessionPath, m.sessionKey); err != nil {
+		m.tempErrors = pushError(err, m.tempErrors)
+	}
+}
+
+// scheduleSessionSave appends a debounced session-save command to cmds,
+// unless one is already pending or persistence is disabled for this run.
+func (m *bblModel) scheduleSessionSave(cmds *[]tea.Cmd) {
+	if m.sessionPath == "" || m.sessionSavePending {
+		return
+	}
+
+	m.sessionSavePending = true
+	*cmds = append(*cmds, debounceSessionCmd())
+}
+
+// ensureDiffTarget picks a sane diff target for col: target itself if it's
+// still a valid, different-from-cursor index, otherwise the next file in
+// the collision.
+func ensureDiffTarget(col *collision, target int) int {
+	cursor := col.fileChoices.cursor
+
+	if len(col.fileChoices.files) < 2 {
+		return cursor
+	}
+
+	if target < 0 || target >= len(col.fileChoices.files) || target == cursor {
+		return (cursor + 1) % len(col.fileChoices.files)
+	}
+
+	return target
+}
+
 func (m bblModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	var cmds []tea.Cmd
 
@@ -303,27 +599,199 @@ func (m bblModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		// Styles
 		m.widthStyle = m.widthStyle.Width(msg.Width)
 	case tea.KeyMsg:
+		if m.deleting {
+			if key.Matches(msg, m.keymap.quit) {
+				return m, tea.Quit
+			}
+			break
+		}
+
+		if m.collisionChoices.Empty() {
+			if key.Matches(msg, m.keymap.quit) {
+				return m, tea.Quit
+			}
+			break
+		}
+
+		if m.listView {
+			switch {
+			case key.Matches(msg, m.keymap.down):
+				m.listViewCursor = (m.listViewCursor + 1) % len(m.collisionChoices.collisions)
+			case key.Matches(msg, m.keymap.up):
+				m.listViewCursor--
+				if m.listViewCursor < 0 {
+					m.listViewCursor = len(m.collisionChoices.collisions) - 1
+				}
+			case key.Matches(msg, m.keymap.enter):
+				m.collisionChoices.CurrentCollision().reviewed = true
+				m.collisionChoices.cursor = m.listViewCursor
+				m.listView = false
+				cmds = append(cmds, CheckFilesExistsCmd(m.collisionChoices.CurrentCollision().fileChoices.files))
+				if m.verify {
+					cmds = append(cmds, VerifyCollisionCmd(m.doppelFile, m.collisionChoices.CurrentCollision().hash, m.verifyFallbackAlgo))
+				}
+				m.scheduleSessionSave(&cmds)
+			case key.Matches(msg, m.keymap.listView):
+				m.listView = false
+			case key.Matches(msg, m.keymap.quit):
+				m.persistSessionOnQuit()
+				return m, tea.Quit
+			}
+
+			return m, tea.Batch(cmds...)
+		}
+
 		switch {
 		case key.Matches(msg, m.keymap.down):
 			m.collisionChoices.CurrentCollision().fileChoices.Next()
 		case key.Matches(msg, m.keymap.up):
 			m.collisionChoices.CurrentCollision().fileChoices.Prev()
 		case key.Matches(msg, m.keymap.right):
+			m.collisionChoices.CurrentCollision().reviewed = true
 			m.collisionChoices.Next()
 			cmds = append(cmds, CheckFilesExistsCmd(m.collisionChoices.CurrentCollision().fileChoices.files))
+			if m.verify {
+				cmds = append(cmds, VerifyCollisionCmd(m.doppelFile, m.collisionChoices.CurrentCollision().hash, m.verifyFallbackAlgo))
+			}
+			m.scheduleSessionSave(&cmds)
 		case key.Matches(msg, m.keymap.left):
+			m.collisionChoices.CurrentCollision().reviewed = true
 			m.collisionChoices.Prev()
 			cmds = append(cmds, CheckFilesExistsCmd(m.collisionChoices.CurrentCollision().fileChoices.files))
+			if m.verify {
+				cmds = append(cmds, VerifyCollisionCmd(m.doppelFile, m.collisionChoices.CurrentCollision().hash, m.verifyFallbackAlgo))
+			}
+			m.scheduleSessionSave(&cmds)
 		case key.Matches(msg, m.keymap.toggle):
 			m.collisionChoices.CurrentCollision().fileChoices.CurrentFile().selected = !m.collisionChoices.CurrentCollision().fileChoices.CurrentFile().selected
+			m.scheduleSessionSave(&cmds)
+		case key.Matches(msg, m.keymap.autoSelect):
+			if err := m.autoSelectCurrentCollision(); err != nil {
+				m.tempErrors = pushError(err, m.tempErrors)
+			}
+			m.scheduleSessionSave(&cmds)
+		case key.Matches(msg, m.keymap.previewToggle):
+			m.showPreview = !m.showPreview
+			if !m.showPreview {
+				m.preview = nil
+				m.diff = nil
+			}
+		case key.Matches(msg, m.keymap.diffCycle):
+			col := m.collisionChoices.CurrentCollision()
+			if len(col.fileChoices.files) > 1 {
+				next := ensureDiffTarget(col, m.diffTarget) + 1
+				if next == col.fileChoices.cursor {
+					next++
+				}
+				m.diffTarget = next % len(col.fileChoices.files)
+			}
+		case key.Matches(msg, m.keymap.listView):
+			m.listView = true
+			m.listViewCursor = m.collisionChoices.cursor
 		case key.Matches(msg, m.keymap.help):
 			m.help.ShowAll = !m.help.ShowAll
 		case key.Matches(msg, m.keymap.quit):
+			m.persistSessionOnQuit()
 			return m, tea.Quit
 		case key.Matches(msg, m.keymap.enter):
 			m.confirmedChoices = true
-			return m, tea.Quit
+			m.deleting = true
+
+			var deleteCmds []tea.Cmd
+			for _, col := range m.collisionChoices.collisions {
+				var keep string
+				for _, f := range col.fileChoices.files {
+					if f.selected {
+						keep = f.name
+						break
+					}
+				}
+
+				for _, f := range col.fileChoices.files {
+					if f.selected {
+						continue
+					}
+
+					m.deleteTotal++
+					deleteCmds = append(deleteCmds, DeleteFileCmd(m.deleterBackend, m.journal, col.hash, f.name, keep))
+				}
+			}
+
+			if m.deleteTotal == 0 {
+				m.clearSessionOnCompletion()
+				return m, tea.Quit
+			}
+
+			return m, tea.Batch(deleteCmds...)
+		}
+
+		if m.showPreview {
+			col := m.collisionChoices.CurrentCollision()
+			cur := col.fileChoices.CurrentFile()
+			cmds = append(cmds, previewCmd(cur))
+
+			m.diffTarget = ensureDiffTarget(col, m.diffTarget)
+			if len(col.fileChoices.files) > 1 {
+				cmds = append(cmds, diffCmd(cur, col.fileChoices.files[m.diffTarget]))
+			}
 		}
+	case nextCollisionMsg:
+		if msg.coll == nil {
+			m.streaming = false
+			if msg.err != nil {
+				m.tempErrors = pushError(msg.err, m.tempErrors)
+			}
+			if err := m.closer.Close(); err != nil {
+				m.tempErrors = pushError(err, m.tempErrors)
+			}
+			break
+		}
+
+		if err := m.doppelFile.Append(msg.coll); err != nil {
+			m.tempErrors = pushError(err, m.tempErrors)
+			break
+		}
+
+		col := &collision{
+			hash: msg.coll.Hash,
+			fileChoices: filesChoice{
+				cursor: 0,
+				files:  make([]*filename, 0, len(msg.coll.Filenames)),
+			},
+		}
+		for _, name := range msg.coll.Filenames {
+			col.fileChoices.files = append(col.fileChoices.files, &filename{name: name, selected: true, valid: true})
+		}
+
+		if restored, ok := m.restoreData[col.hash]; ok {
+			selected := make(map[string]bool, len(restored.Selected))
+			for _, name := range restored.Selected {
+				selected[name] = true
+			}
+			for _, f := range col.fileChoices.files {
+				f.selected = selected[f.name]
+			}
+			col.reviewed = restored.Reviewed
+		}
+
+		wasEmpty := m.collisionChoices.Empty()
+		m.collisionChoices.collisions = append(m.collisionChoices.collisions, col)
+
+		restoringCursor := false
+		if m.restoreCursor >= 0 && len(m.collisionChoices.collisions)-1 == m.restoreCursor {
+			m.collisionChoices.cursor = m.restoreCursor
+			m.restoreCursor = -1
+			restoringCursor = true
+		}
+
+		if wasEmpty || restoringCursor {
+			cmds = append(cmds, CheckFilesExistsCmd(m.collisionChoices.CurrentCollision().fileChoices.files))
+			if m.verify {
+				cmds = append(cmds, VerifyCollisionCmd(m.doppelFile, m.collisionChoices.CurrentCollision().hash, m.verifyFallbackAlgo))
+			}
+		}
+
+		cmds = append(cmds, ReceiveCollisionCmd(m.collisionsCh, m.parseErrsCh))
 	case fileExistsResponse:
 		if msg.err != nil {
 			m.tempErrors = pushError(msg.err, m.tempErrors)
@@ -336,12 +804,72 @@ func (m bblModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		for _, f := range msg.not_exists {
 			f.valid = false
 		}
+	case hashVerifiedResponse:
+		if msg.hash != m.collisionChoices.CurrentCollision().hash {
+			break
+		}
+
+		if msg.err != nil {
+			m.tempErrors = pushError(msg.err, m.tempErrors)
+			break
+		}
+
+		byName := make(map[string]*filename, len(m.collisionChoices.CurrentCollision().fileChoices.files))
+		for _, f := range m.collisionChoices.CurrentCollision().fileChoices.files {
+			byName[f.name] = f
+		}
+
+		for _, result := range msg.results {
+			f, ok := byName[result.Filename]
+			if !ok {
+				continue
+			}
+
+			if result.Err != nil {
+				m.tempErrors = pushError(result.Err, m.tempErrors)
+				continue
+			}
+
+			f.valid = f.valid && result.Match
+		}
+	case previewResponse:
+		m.preview = &msg.preview
+	case diffResponse:
+		m.diff = &msg
+	case saveSessionMsg:
+		m.sessionSavePending = false
+		m.persistSessionOnQuit()
+	case deleteResultMsg:
+		m.deleteDone++
+		if msg.err != nil {
+			m.deleteHadError = true
+			m.deleteLog = append(m.deleteLog, fmt.Sprintf("error while removing %s: %v", msg.path, msg.err))
+		} else {
+			m.deleteLog = append(m.deleteLog, fmt.Sprintf("removed %s", msg.path))
+		}
+
+		if m.deleteDone >= m.deleteTotal {
+			m.clearSessionOnCompletion()
+			return m, tea.Quit
+		}
 	}
 
 	return m, tea.Batch(cmds...)
 }
 
 func (m bblModel) View() string {
+	if m.deleting {
+		return m.widthStyle.Render(m.deletingView())
+	}
+
+	if m.collisionChoices.Empty() {
+		return m.widthStyle.Render("Waiting for the first collision to load...\n")
+	}
+
+	if m.listView {
+		return m.widthStyle.Render(m.listViewRender())
+	}
+
 	var result strings.Builder
 
 	result.WriteString("Select all you want to ")
@@ -393,5 +921,112 @@ func (m bblModel) View() string {
 		}
 	}
 
+	if m.showPreview {
+		joined := lipgloss.JoinHorizontal(lipgloss.Top, result.String(), "    ", m.previewPaneView())
+		return m.widthStyle.Render(joined)
+	}
+
 	return m.widthStyle.Render(result.String())
 }
+
+// deletingView renders the progress of removing duplicates through
+// m.deleterBackend, once choices have been confirmed.
+func (m bblModel) deletingView() string {
+	var result strings.Builder
+
+	result.WriteString(fmt.Sprintf("Removing duplicates via %s...\n\n", m.deleterBackend.Name()))
+	result.WriteString(
+		m.progressCountStyle.Render(fmt.Sprintf("%d/%d", m.deleteDone, m.deleteTotal)),
+	)
+	result.WriteString("\n")
+	result.WriteString(m.progress.ViewAs(float64(m.deleteDone) / float64(m.deleteTotal)))
+	result.WriteString("\n")
+
+	for _, line := range m.deleteLog {
+		result.WriteString(m.normalStyle.Render(line))
+		result.WriteString("\n")
+	}
+
+	return result.String()
+}
+
+// listViewRender renders the list of every collision seen so far, marking
+// which ones are still pending first review, so the user can jump straight
+// to unfinished work instead of paging through left/right one at a time.
+func (m bblModel) listViewRender() string {
+	var result strings.Builder
+
+	result.WriteString(m.boldStyle.Render("Jump to a collision"))
+	result.WriteString("\n\n")
+
+	for i, col := range m.collisionChoices.collisions {
+		cursorSymbol := " "
+		if i == m.listViewCursor {
+			cursorSymbol = ">"
+		}
+
+		reviewedSymbol := " "
+		lineStyle := m.invalidStyle
+		if col.reviewed {
+			reviewedSymbol = "x"
+			lineStyle = m.normalStyle
+		}
+
+		line := fmt.Sprintf("%s [%s] %s", cursorSymbol, reviewedSymbol, col.hash)
+		result.WriteString(lineStyle.Render(line))
+		result.WriteString("\n")
+	}
+
+	result.WriteString("\n")
+	result.WriteString(m.help.View(m.keymap))
+
+	return result.String()
+}
+
+// previewPaneView renders the metadata and diff panel shown next to the
+// collision list while m.showPreview is set.
+func (m bblModel) previewPaneView() string {
+	var result strings.Builder
+
+	result.WriteString(m.boldStyle.Render("Preview"))
+	result.WriteString("\n\n")
+
+	if m.preview == nil {
+		result.WriteString(m.normalStyle.Render("loading..."))
+		return result.String()
+	}
+
+	if m.preview.err != nil {
+		result.WriteString(m.invalidStyle.Render(fmt.Sprintf("error: %v", m.preview.err)))
+		return result.String()
+	}
+
+	result.WriteString(fmt.Sprintf("size:     %d bytes\n", m.preview.size))
+	result.WriteString(fmt.Sprintf("modified: %s\n", m.preview.modTime.Format(time.RFC3339)))
+	result.WriteString(fmt.Sprintf("mode:     %s\n", m.preview.mode))
+	result.WriteString(fmt.Sprintf("mimetype: %s\n", m.preview.mimeType))
+
+	if m.diff == nil {
+		return result.String()
+	}
+
+	result.WriteString("\n")
+	result.WriteString(m.boldStyle.Render(fmt.Sprintf("diff vs %s", m.diff.target)))
+	result.WriteString("\n")
+
+	if m.diff.err != nil {
+		result.WriteString(m.invalidStyle.Render(fmt.Sprintf("error: %v", m.diff.err)))
+		return result.String()
+	}
+
+	for _, line := range m.diff.lines {
+		lineStyle := m.normalStyle
+		if line.marker != ' ' {
+			lineStyle = m.invalidStyle
+		}
+		result.WriteString(lineStyle.Render(fmt.Sprintf("%c %s", line.marker, line.text)))
+		result.WriteString("\n")
+	}
+
+	return result.String()
+}
@@ -6,62 +6,146 @@ import (
 	"os"
 
 	"github.com/alecthomas/kong"
+	"github.com/blackhawk42/doppelrm/pkg/deleter"
 	"github.com/blackhawk42/doppelrm/pkg/doppelparser"
 	tea "github.com/charmbracelet/bubbletea"
 )
 
-type Cli struct {
-	File string `arg:"" type:"existingfile" help:"The doppel output file. \"-\" means stdin."`
+// RmCmd is the default command: interactively review a doppel output and
+// remove the files the user didn't choose to keep.
+type RmCmd struct {
+	File       string `arg:"" type:"existingfile" help:"The doppel output file. \"-\" means stdin."`
+	Verify     bool   `help:"Re-verify each file's digest against its recorded hash as you navigate, flagging mismatches alongside missing files."`
+	VerifyAlgo string `help:"Hash algorithm to assume with --verify when a hash's algorithm can't be determined from the report itself (no algo: header, no per-hash prefix)." enum:"md5,sha1,sha256,blake3,xxhash,"`
+	Trash      string `help:"How to get rid of files that aren't kept." enum:"xdg,recycle,remove,hardlink" default:"remove"`
+	Journal    string `help:"Where to record the undo journal." default:"doppelrm-undo.jsonl"`
+
+	NonInteractive bool     `help:"Skip the TUI and resolve every collision automatically using --policy."`
+	Policy         string   `help:"Policy used to auto-resolve a collision, with --non-interactive or the TUI's auto-select key." enum:"first,shortest-path,oldest,newest,in-directory,prefer" default:"first"`
+	KeepInDir      string   `help:"Directory to prefer when --policy=in-directory."`
+	Prefer         []string `help:"Regex to prefer, in priority order, when --policy=prefer. Repeatable."`
+	DryRun         bool     `help:"With --non-interactive, print the planned removals as JSON instead of removing anything."`
+
+	Session string `help:"Where to persist the user's selections across runs, so quitting or crashing doesn't lose progress on a large report. Defaults to $XDG_STATE_HOME/doppelrm/session.json."`
 }
 
-func main() {
-	var cli Cli
-	kongCtx := kong.Parse(
-		&cli,
-		kong.Description("Interactively delete files from a doppel output"),
-	)
+func (cmd *RmCmd) Run(kongCtx *kong.Context) error {
+	backend, err := deleter.Lookup(cmd.Trash)
+	if err != nil {
+		return err
+	}
 
-	// Parse doppel file
 	var input io.ReadCloser
-	if cli.File == "-" {
+	if cmd.File == "-" {
 		input = io.NopCloser(os.Stdin)
 	} else {
-		f, err := os.Open(cli.File)
-		kongCtx.FatalIfErrorf(err, fmt.Sprintf("while opening file %s: %v", cli.File, err))
+		f, err := os.Open(cmd.File)
+		if err != nil {
+			return fmt.Errorf("while opening file %s: %w", cmd.File, err)
+		}
 
 		input = f
 	}
 
-	doppelFile, err := doppelparser.ParseDoppelFile(input)
-	kongCtx.FatalIfErrorf(err, fmt.Sprintf("while reading input file %s: %v", cli.File, err))
-	input.Close()
+	p, err := buildPolicy(cmd)
+	if err != nil {
+		input.Close()
+		return err
+	}
 
-	// Setup and start Bubbletea
-	model := newBblModel(doppelFile)
-	bblProgram := tea.NewProgram(model)
-	m, err := bblProgram.Run()
-	kongCtx.FatalIfErrorf(err, fmt.Sprintf("while starting Bubbletea program: %v", err))
-	model = m.(bblModel)
-	kongCtx.FatalIfErrorf(model.finalErr, fmt.Sprintf("%v", model.finalErr))
+	verifyFallbackAlgo := doppelparser.HashAlgoUnknown
+	if cmd.VerifyAlgo != "" {
+		verifyFallbackAlgo, err = doppelparser.ParseHashAlgo(cmd.VerifyAlgo)
+		if err != nil {
+			input.Close()
+			return err
+		}
+	}
 
-	// If terminated without confirming choices, just quit and do nothing
-	if !model.confirmedChoices {
-		return
+	if cmd.NonInteractive {
+		doppelFile, err := doppelparser.ParseDoppelFile(input)
+		input.Close()
+		if err != nil {
+			return fmt.Errorf("while reading input file %s: %w", cmd.File, err)
+		}
+
+		if cmd.DryRun {
+			return runNonInteractive(doppelFile, p, backend, nil, true)
+		}
+
+		journal, err := deleter.OpenJournal(cmd.Journal)
+		if err != nil {
+			return err
+		}
+		defer journal.Close()
+
+		return runNonInteractive(doppelFile, p, backend, journal, false)
 	}
 
-	// Delete all files that weren't selected
-	for _, col := range model.collisionChoices.collisions {
-		for _, file := range col.fileChoices.files {
-			if !file.selected {
-				err = os.Remove(file.name)
-				if err == nil {
-					fmt.Fprintf(os.Stderr, "removed %s\n", file.name)
-				} else {
-					fmt.Fprintf(os.Stderr, "error while removing %s: %v\n", file.name, err)
-				}
-			} else {
-				fmt.Fprintf(os.Stderr, "kept %s\n", file.name)
-			}
+	journal, err := deleter.OpenJournal(cmd.Journal)
+	if err != nil {
+		input.Close()
+		return err
+	}
+	defer journal.Close()
+
+	sessionPath := cmd.Session
+	if sessionPath == "" {
+		sessionPath = defaultSessionPath()
+	}
+
+	sessionKey, ok := sessionKeyFor(cmd.File)
+	if !ok {
+		// Nothing stable to key a session on, e.g. input is stdin.
+		sessionPath = ""
+	}
+
+	var sess *sessionData
+	if sessionPath != "" {
+		sess, err = loadSession(sessionPath, sessionKey)
+		if err != nil {
+			input.Close()
+			return err
 		}
 	}
+
+	// Collisions stream in as the Bubbletea program runs, rather than
+	// waiting on the whole file; the model closes input once the stream
+	// is exhausted.
+	collisions, parseErrs := doppelparser.ParseDoppelStream(input)
+	model := newBblModel(collisions, parseErrs, input, cmd.Verify, verifyFallbackAlgo, backend, journal, p, sessionPath, sessionKey, sess)
+	bblProgram := tea.NewProgram(model)
+	m, err := bblProgram.Run()
+	if err != nil {
+		return fmt.Errorf("while starting Bubbletea program: %w", err)
+	}
+	model = m.(bblModel)
+
+	return model.finalErr
+}
+
+// UndoCmd restores files that were removed in a previous run, by replaying
+// its undo journal in reverse.
+type UndoCmd struct {
+	Journal string `arg:"" type:"existingfile" help:"The undo journal written by a previous run."`
+}
+
+func (cmd *UndoCmd) Run(kongCtx *kong.Context) error {
+	return deleter.Undo(cmd.Journal)
+}
+
+type Cli struct {
+	Rm   RmCmd   `cmd:"" default:"withargs" help:"Interactively delete files from a doppel output (default)."`
+	Undo UndoCmd `cmd:"" help:"Restore files recorded in an undo journal."`
+}
+
+func main() {
+	var cli Cli
+	kongCtx := kong.Parse(
+		&cli,
+		kong.Description("Interactively delete files from a doppel output"),
+	)
+
+	err := kongCtx.Run()
+	kongCtx.FatalIfErrorf(err)
 }
@@ -0,0 +1,168 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// previewSampleSize is how much of a file is read to sniff its mimetype,
+// matching the most net/http.DetectContentType looks at.
+const previewSampleSize = 512
+
+// diffSampleSize bounds how much of each file is read for a diff, so
+// previewing a multi-gigabyte file doesn't stall the TUI.
+const diffSampleSize = 64 * 1024
+
+// filePreview is the metadata shown in the preview pane for a single file.
+type filePreview struct {
+	path     string
+	size     int64
+	modTime  time.Time
+	mode     os.FileMode
+	mimeType string
+	err      error
+}
+
+// previewResponse is a response message to a previewCmd command.
+type previewResponse struct {
+	preview filePreview
+}
+
+// previewCmd gathers size, mtime, permissions and mimetype for f.
+//
+// Final command returns a previewResponse message, same async pattern as
+// CheckFilesExistsCmd.
+func previewCmd(f *filename) tea.Cmd {
+	return func() tea.Msg {
+		p := filePreview{path: f.name}
+
+		info, err := os.Stat(f.name)
+		if err != nil {
+			p.err = err
+			return tea.Msg(previewResponse{preview: p})
+		}
+		p.size = info.Size()
+		p.modTime = info.ModTime()
+		p.mode = info.Mode()
+
+		file, err := os.Open(f.name)
+		if err != nil {
+			p.err = err
+			return tea.Msg(previewResponse{preview: p})
+		}
+		defer file.Close()
+
+		buf := make([]byte, previewSampleSize)
+		n, _ := file.Read(buf)
+		p.mimeType = http.DetectContentType(buf[:n])
+
+		return tea.Msg(previewResponse{preview: p})
+	}
+}
+
+// diffLine is a single line of a line-level diff, marked ' ' for unchanged,
+// '-' for only in the left file, or '+' for only in the right one.
+type diffLine struct {
+	marker byte
+	text   string
+}
+
+// diffResponse is a response message to a diffCmd command.
+type diffResponse struct {
+	target string
+	lines  []diffLine
+	err    error
+}
+
+// diffCmd compares a and b line by line, for the preview pane's diff view.
+// It refuses to diff anything that doesn't look like text.
+//
+// Final command returns a diffResponse message, same async pattern as
+// CheckFilesExistsCmd.
+func diffCmd(a, b *filename) tea.Cmd {
+	return func() tea.Msg {
+		resp := diffResponse{target: b.name}
+
+		sampleA, err := readSample(a.name)
+		if err != nil {
+			resp.err = err
+			return tea.Msg(resp)
+		}
+
+		sampleB, err := readSample(b.name)
+		if err != nil {
+			resp.err = err
+			return tea.Msg(resp)
+		}
+
+		if !looksLikeText(sampleA) || !looksLikeText(sampleB) {
+			resp.err = fmt.Errorf("cannot diff binary files")
+			return tea.Msg(resp)
+		}
+
+		resp.lines = lineDiff(sampleA, sampleB)
+
+		return tea.Msg(resp)
+	}
+}
+
+func readSample(path string) ([]byte, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	buf := make([]byte, diffSampleSize)
+	n, err := f.Read(buf)
+	if err != nil && !errors.Is(err, io.EOF) {
+		return nil, err
+	}
+
+	return buf[:n], nil
+}
+
+func looksLikeText(sample []byte) bool {
+	return !bytes.Contains(sample, []byte{0})
+}
+
+// lineDiff is a naive, positional line-by-line comparison: it doesn't find
+// the shortest edit script the way a real diff would, but it's enough to
+// spot where two same-hash files actually differ without leaving the TUI.
+func lineDiff(a, b []byte) []diffLine {
+	linesA := strings.Split(string(a), "\n")
+	linesB := strings.Split(string(b), "\n")
+
+	max := len(linesA)
+	if len(linesB) > max {
+		max = len(linesB)
+	}
+
+	result := make([]diffLine, 0, max)
+	for i := 0; i < max; i++ {
+		hasA := i < len(linesA)
+		hasB := i < len(linesB)
+
+		if hasA && hasB && linesA[i] == linesB[i] {
+			result = append(result, diffLine{marker: ' ', text: linesA[i]})
+			continue
+		}
+
+		if hasA {
+			result = append(result, diffLine{marker: '-', text: linesA[i]})
+		}
+		if hasB {
+			result = append(result, diffLine{marker: '+', text: linesB[i]})
+		}
+	}
+
+	return result
+}
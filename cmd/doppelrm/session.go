@@ -0,0 +1,217 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// sessionSaveDebounce is how long after the first unsaved model update the
+// session file is actually written; updates in the meantime don't restart the
+// wait, they are picked up by that same write. This keeps rapid navigation
+// from turning into a write per keystroke.
+const sessionSaveDebounce = 500 * time.Millisecond
+
+// collisionSession is the persisted state of a single collision.
+type collisionSession struct {
+	Selected []string `json:"selected"`
+	Reviewed bool     `json:"reviewed"`
+}
+
+// sessionData is the full on-disk session file: enough to restore the
+// cursor position and every collision's selections and review status.
+type sessionData struct {
+	Cursor     int                         `json:"cursor"`
+	Collisions map[string]collisionSession `json:"collisions"`
+}
+
+// xdgStateHome mirrors xdgDataHome in pkg/deleter: $XDG_STATE_HOME, falling
+// back to ~/.local/state per the XDG base directory spec.
+func xdgStateHome() string {
+	if dir := os.Getenv("XDG_STATE_HOME"); dir != "" {
+		return dir
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ".local/state"
+	}
+
+	return filepath.Join(home, ".local", "state")
+}
+
+// defaultSessionPath is where the session file lives when --session isn't
+// given explicitly.
+func defaultSessionPath() string {
+	return filepath.Join(xdgStateHome(), "doppelrm", "session.json")
+}
+
+// sessionKeyFor derives a stable key for file, so a session file can hold
+// state for more than one doppel report. It's based on the file's path,
+// size and modtime rather than its full content: doppel reports can be
+// multi-GB, and ParseDoppelStream is built specifically to avoid reading
+// those wholesale just to start the TUI.
+//
+// ok is false when file is "-" (stdin) or can't be stat'd, in which case
+// there's nothing stable to key a session on.
+func sessionKeyFor(file string) (key string, ok bool) {
+	if file == "-" {
+		return "", false
+	}
+
+	info, err := os.Stat(file)
+	if err != nil {
+		return "", false
+	}
+
+	abs, err := filepath.Abs(file)
+	if err != nil {
+		abs = file
+	}
+
+	sum := sha256.Sum256([]byte(fmt.Sprintf("%s|%d|%d", abs, info.Size(), info.ModTime().UnixNano())))
+
+	return hex.EncodeToString(sum[:]), true
+}
+
+// loadSession reads the session file at path and returns the entry for key,
+// if any. It is not an error for the file or the entry to not exist; both
+// cases just return a nil *sessionData.
+func loadSession(path string, key string) (*sessionData, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("while reading session file %s: %w", path, err)
+	}
+
+	var store map[string]sessionData
+	if err := json.Unmarshal(raw, &store); err != nil {
+		return nil, fmt.Errorf("while parsing session file %s: %w", path, err)
+	}
+
+	entry, ok := store[key]
+	if !ok {
+		return nil, nil
+	}
+
+	return &entry, nil
+}
+
+// saveSession atomically writes data under key into the session file at
+// path, preserving whatever other keys were already there.
+func saveSession(path string, key string, data sessionData) error {
+	store := make(map[string]sessionData)
+
+	if raw, err := os.ReadFile(path); err == nil {
+		if err := json.Unmarshal(raw, &store); err != nil {
+			return fmt.Errorf("while parsing session file %s: %w", path, err)
+		}
+	} else if !os.IsNotExist(err) {
+		return fmt.Errorf("while reading session file %s: %w", path, err)
+	}
+
+	store[key] = data
+
+	return writeSessionStore(path, store)
+}
+
+// clearSession removes key from the session file at path, leaving any other
+// keys intact. It is not an error for the file or the key to already be
+// absent; used once a run's deletions all complete, so a stale entry isn't
+// left behind to be wrongly restored on a future run against the same file.
+func clearSession(path string, key string) error {
+	store := make(map[string]sessionData)
+
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return fmt.Errorf("while reading session file %s: %w", path, err)
+	}
+	if err := json.Unmarshal(raw, &store); err != nil {
+		return fmt.Errorf("while parsing session file %s: %w", path, err)
+	}
+
+	if _, ok := store[key]; !ok {
+		return nil
+	}
+	delete(store, key)
+
+	return writeSessionStore(path, store)
+}
+
+// writeSessionStore atomically replaces the session file at path with store.
+func writeSessionStore(path string, store map[string]sessionData) error {
+	raw, err := json.MarshalIndent(store, "", "  ")
+	if err != nil {
+		return fmt.Errorf("while encoding session file: %w", err)
+	}
+
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return fmt.Errorf("while creating session dir %s: %w", dir, err)
+	}
+
+	tmp, err := os.CreateTemp(dir, ".session-*.json")
+	if err != nil {
+		return fmt.Errorf("while creating temp session file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(raw); err != nil {
+		tmp.Close()
+		return fmt.Errorf("while writing temp session file: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("while closing temp session file: %w", err)
+	}
+
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("while replacing session file %s: %w", path, err)
+	}
+
+	return nil
+}
+
+// buildSessionData snapshots m's current cursor and per-collision selections
+// and review status into the shape written to the session file.
+func (m bblModel) buildSessionData() sessionData {
+	data := sessionData{
+		Cursor:     m.collisionChoices.cursor,
+		Collisions: make(map[string]collisionSession, len(m.collisionChoices.collisions)),
+	}
+
+	for _, col := range m.collisionChoices.collisions {
+		var selected []string
+		for _, f := range col.fileChoices.files {
+			if f.selected {
+				selected = append(selected, f.name)
+			}
+		}
+
+		data.Collisions[col.hash] = collisionSession{Selected: selected, Reviewed: col.reviewed}
+	}
+
+	return data
+}
+
+// saveSessionMsg fires once the debounce timer set by debounceSessionCmd
+// elapses.
+type saveSessionMsg struct{}
+
+// debounceSessionCmd schedules a single saveSessionMsg sessionSaveDebounce
+// from now.
+func debounceSessionCmd() tea.Cmd {
+	return tea.Tick(sessionSaveDebounce, func(time.Time) tea.Msg {
+		return saveSessionMsg{}
+	})
+}
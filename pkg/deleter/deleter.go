@@ -0,0 +1,150 @@
+// Package deleter removes duplicate files through one of several pluggable
+// backends, recording every action in an undo journal so it can later be
+// reversed with Undo.
+package deleter
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+)
+
+// Backend is a pluggable strategy for getting rid of a duplicate file.
+type Backend interface {
+	// Name identifies the backend, as used in the --trash flag and in undo
+	// journal entries.
+	Name() string
+
+	// Remove gets rid of path. keep is the file this duplicate was chosen
+	// over; backends that don't need it (most of them) ignore it. trashLocation
+	// is where the file ended up, if anywhere, and is recorded verbatim in the
+	// undo journal so Restore can find it again.
+	Remove(path string, keep string) (trashLocation string, err error)
+
+	// Restore reverses a single Remove, given the original path and the
+	// trashLocation that was recorded for it.
+	Restore(originalPath string, trashLocation string) error
+}
+
+var backends = map[string]Backend{}
+
+// register adds a backend to the registry under its own Name.
+func register(b Backend) {
+	backends[b.Name()] = b
+}
+
+// Lookup returns the registered backend for name, e.g. as supplied to the
+// --trash flag.
+func Lookup(name string) (Backend, error) {
+	b, ok := backends[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown deletion backend: %q", name)
+	}
+
+	return b, nil
+}
+
+// Names lists every registered backend name, in no particular order.
+func Names() []string {
+	names := make([]string, 0, len(backends))
+	for name := range backends {
+		names = append(names, name)
+	}
+
+	return names
+}
+
+// JournalEntry is a single recorded action, as written to an undo journal.
+type JournalEntry struct {
+	Hash          string    `json:"hash"`
+	OriginalPath  string    `json:"original_path"`
+	Backend       string    `json:"backend"`
+	Timestamp     time.Time `json:"timestamp"`
+	TrashLocation string    `json:"trash_location,omitempty"`
+}
+
+// Journal is an append-only, JSON-lines undo log of every deletion made
+// during a run.
+type Journal struct {
+	f   *os.File
+	enc *json.Encoder
+}
+
+// OpenJournal opens (creating if necessary) the journal file at path for
+// appending.
+func OpenJournal(path string) (*Journal, error) {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return nil, fmt.Errorf("while opening undo journal %s: %w", path, err)
+	}
+
+	return &Journal{f: f, enc: json.NewEncoder(f)}, nil
+}
+
+// Record appends entry to the journal.
+func (j *Journal) Record(entry JournalEntry) error {
+	if err := j.enc.Encode(entry); err != nil {
+		return fmt.Errorf("while writing undo journal entry: %w", err)
+	}
+
+	return nil
+}
+
+// Close closes the underlying journal file.
+func (j *Journal) Close() error {
+	return j.f.Close()
+}
+
+// ReadJournal reads every entry of the journal file at path, in the order
+// they were written.
+func ReadJournal(path string) ([]JournalEntry, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("while opening undo journal %s: %w", path, err)
+	}
+	defer f.Close()
+
+	var entries []JournalEntry
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var entry JournalEntry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			return entries, fmt.Errorf("while parsing undo journal %s: %w", path, err)
+		}
+
+		entries = append(entries, entry)
+	}
+	if err := scanner.Err(); err != nil {
+		return entries, fmt.Errorf("while reading undo journal %s: %w", path, err)
+	}
+
+	return entries, nil
+}
+
+// Undo restores every entry recorded in the journal at path, in reverse
+// order, using each entry's own backend.
+func Undo(path string) error {
+	entries, err := ReadJournal(path)
+	if err != nil {
+		return err
+	}
+
+	var undoErr error
+	for i := len(entries) - 1; i >= 0; i-- {
+		entry := entries[i]
+
+		b, err := Lookup(entry.Backend)
+		if err != nil {
+			undoErr = fmt.Errorf("while restoring %s: %w", entry.OriginalPath, err)
+			continue
+		}
+
+		if err := b.Restore(entry.OriginalPath, entry.TrashLocation); err != nil {
+			undoErr = fmt.Errorf("while restoring %s: %w", entry.OriginalPath, err)
+		}
+	}
+
+	return undoErr
+}
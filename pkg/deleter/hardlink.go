@@ -0,0 +1,54 @@
+package deleter
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// hardlinkReplace replaces a duplicate with a hardlink to the file it was
+// kept in favor of, reclaiming the space without losing the path.
+type hardlinkReplace struct{}
+
+func init() {
+	register(hardlinkReplace{})
+}
+
+func (hardlinkReplace) Name() string {
+	return "hardlink"
+}
+
+func (hardlinkReplace) Remove(path string, keep string) (string, error) {
+	if keep == "" {
+		return "", fmt.Errorf("hardlink backend needs a file to keep, none given for %s", path)
+	}
+
+	// Link into a temp name next to path first and only swap it in via
+	// Rename once that succeeds, so a failure (e.g. EXDEV, when keep is on
+	// a different filesystem) never leaves path deleted with nothing to
+	// put back. os.Remove-then-Link would destroy the original on exactly
+	// that failure.
+	tmp := filepath.Join(filepath.Dir(path), "."+filepath.Base(path)+".doppelrm-hardlink-tmp")
+	if err := os.Link(keep, tmp); err != nil {
+		return "", fmt.Errorf("while hardlinking %s to %s: %w", path, keep, err)
+	}
+
+	if err := os.Rename(tmp, path); err != nil {
+		os.Remove(tmp)
+		return "", fmt.Errorf("while replacing %s with hardlink: %w", path, err)
+	}
+
+	// trashLocation doubles as the canonical file the link points to, so
+	// Restore knows what to undo.
+	return keep, nil
+}
+
+func (hardlinkReplace) Restore(originalPath string, trashLocation string) error {
+	if err := os.Remove(originalPath); err != nil {
+		return err
+	}
+
+	// The original content is gone for good; the best we can do is leave the
+	// hardlink in place rather than leaving no file at all.
+	return os.Link(trashLocation, originalPath)
+}
@@ -0,0 +1,30 @@
+package deleter
+
+import (
+	"fmt"
+	"os"
+)
+
+// osRemove is the simplest backend: unconditional os.Remove, with no
+// possibility of undo.
+type osRemove struct{}
+
+func init() {
+	register(osRemove{})
+}
+
+func (osRemove) Name() string {
+	return "remove"
+}
+
+func (osRemove) Remove(path string, keep string) (string, error) {
+	if err := os.Remove(path); err != nil {
+		return "", err
+	}
+
+	return "", nil
+}
+
+func (osRemove) Restore(originalPath string, trashLocation string) error {
+	return fmt.Errorf("%s backend does not support undo: %s was permanently removed", osRemove{}.Name(), originalPath)
+}
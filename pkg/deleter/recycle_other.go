@@ -0,0 +1,25 @@
+//go:build !windows
+
+package deleter
+
+import "fmt"
+
+// recycle is a stand-in for the Windows Recycle Bin backend on platforms
+// that don't have one.
+type recycle struct{}
+
+func init() {
+	register(recycle{})
+}
+
+func (recycle) Name() string {
+	return "recycle"
+}
+
+func (recycle) Remove(path string, keep string) (string, error) {
+	return "", fmt.Errorf("%s backend is only available on Windows", recycle{}.Name())
+}
+
+func (recycle) Restore(originalPath string, trashLocation string) error {
+	return fmt.Errorf("%s backend is only available on Windows", recycle{}.Name())
+}
@@ -0,0 +1,72 @@
+//go:build windows
+
+package deleter
+
+import (
+	"fmt"
+	"syscall"
+	"unsafe"
+)
+
+// recycle sends files to the Windows Recycle Bin via the shell's
+// SHFileOperation, rather than deleting them outright.
+type recycle struct{}
+
+func init() {
+	register(recycle{})
+}
+
+func (recycle) Name() string {
+	return "recycle"
+}
+
+const (
+	foDelete          = 0x0003
+	fofAllowUndo      = 0x0040
+	fofNoConfirmation = 0x0010
+	fofSilent         = 0x0004
+)
+
+// shFileOpStruct mirrors the Win32 SHFILEOPSTRUCTW layout.
+type shFileOpStruct struct {
+	hwnd                  uintptr
+	wFunc                 uint32
+	pFrom                 *uint16
+	pTo                   *uint16
+	fFlags                uint16
+	fAnyOperationsAborted int32
+	hNameMappings         uintptr
+	lpszProgressTitle     *uint16
+}
+
+func (recycle) Remove(path string, keep string) (string, error) {
+	// pFrom must be double-NUL-terminated.
+	from, err := syscall.UTF16FromString(path)
+	if err != nil {
+		return "", fmt.Errorf("while encoding path %s: %w", path, err)
+	}
+	from = append(from, 0)
+
+	op := shFileOpStruct{
+		wFunc:  foDelete,
+		pFrom:  &from[0],
+		fFlags: fofAllowUndo | fofNoConfirmation | fofSilent,
+	}
+
+	shell32 := syscall.NewLazyDLL("shell32.dll")
+	shFileOperationW := shell32.NewProc("SHFileOperationW")
+
+	ret, _, _ := shFileOperationW.Call(uintptr(unsafe.Pointer(&op)))
+	if ret != 0 {
+		return "", fmt.Errorf("SHFileOperationW failed for %s with code %d", path, ret)
+	}
+	if op.fAnyOperationsAborted != 0 {
+		return "", fmt.Errorf("recycling %s was aborted", path)
+	}
+
+	return "", nil
+}
+
+func (recycle) Restore(originalPath string, trashLocation string) error {
+	return fmt.Errorf("%s backend does not support undo: restore %s from the Recycle Bin manually", recycle{}.Name(), originalPath)
+}
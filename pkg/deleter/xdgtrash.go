@@ -0,0 +1,137 @@
+package deleter
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// xdgTrash implements the freedesktop.org trash specification: files are
+// moved to $XDG_DATA_HOME/Trash/files/ and a matching .trashinfo sidecar is
+// written to $XDG_DATA_HOME/Trash/info/ recording the original path and
+// deletion date.
+type xdgTrash struct{}
+
+func init() {
+	register(xdgTrash{})
+}
+
+func (xdgTrash) Name() string {
+	return "xdg"
+}
+
+func xdgDataHome() string {
+	if dir := os.Getenv("XDG_DATA_HOME"); dir != "" {
+		return dir
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ".local/share"
+	}
+
+	return filepath.Join(home, ".local", "share")
+}
+
+func xdgTrashDirs() (files, info string) {
+	base := filepath.Join(xdgDataHome(), "Trash")
+	return filepath.Join(base, "files"), filepath.Join(base, "info")
+}
+
+// reserveTrashName atomically claims a name in filesDir/infoDir based on
+// base, trying base, then base_2, base_3, ... per the trash spec. Both the
+// files/ placeholder and the info/ placeholder are created with
+// O_CREATE|O_EXCL before a candidate is considered claimed, and backed out
+// together if either one loses the race, so two Removes racing on the same
+// basename (the common case for duplicate files trashed concurrently) can
+// never land on the same reservation and clobber each other.
+func reserveTrashName(filesDir string, infoDir string, base string) (trashLocation string, infoLocation string, err error) {
+	ext := filepath.Ext(base)
+	stem := base[:len(base)-len(ext)]
+	candidate := base
+
+	for i := 2; ; i++ {
+		trashLocation = filepath.Join(filesDir, candidate)
+		infoLocation = filepath.Join(infoDir, candidate+".trashinfo")
+
+		fileFile, ferr := os.OpenFile(trashLocation, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0o600)
+		if ferr != nil {
+			if os.IsExist(ferr) {
+				candidate = fmt.Sprintf("%s_%d%s", stem, i, ext)
+				continue
+			}
+			return "", "", ferr
+		}
+		fileFile.Close()
+
+		infoFile, ierr := os.OpenFile(infoLocation, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0o600)
+		if ierr != nil {
+			os.Remove(trashLocation)
+			if os.IsExist(ierr) {
+				candidate = fmt.Sprintf("%s_%d%s", stem, i, ext)
+				continue
+			}
+			return "", "", ierr
+		}
+		infoFile.Close()
+
+		return trashLocation, infoLocation, nil
+	}
+}
+
+func (xdgTrash) Remove(path string, keep string) (string, error) {
+	filesDir, infoDir := xdgTrashDirs()
+	if err := os.MkdirAll(filesDir, 0o700); err != nil {
+		return "", fmt.Errorf("while creating trash files dir: %w", err)
+	}
+	if err := os.MkdirAll(infoDir, 0o700); err != nil {
+		return "", fmt.Errorf("while creating trash info dir: %w", err)
+	}
+
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return "", fmt.Errorf("while resolving absolute path of %s: %w", path, err)
+	}
+
+	trashLocation, infoLocation, err := reserveTrashName(filesDir, infoDir, filepath.Base(absPath))
+	if err != nil {
+		return "", fmt.Errorf("while picking trash name for %s: %w", path, err)
+	}
+
+	info := fmt.Sprintf(
+		"[Trash Info]\nPath=%s\nDeletionDate=%s\n",
+		(&url.URL{Path: absPath}).String(),
+		time.Now().Format("2006-01-02T15:04:05"),
+	)
+	// info and trashLocation are our own just-created placeholders, so
+	// overwriting their (empty) contents here isn't clobbering anyone else's
+	// reservation.
+	if err := os.WriteFile(infoLocation, []byte(info), 0o600); err != nil {
+		os.Remove(infoLocation)
+		os.Remove(trashLocation)
+		return "", fmt.Errorf("while writing trashinfo for %s: %w", path, err)
+	}
+
+	if err := os.Rename(absPath, trashLocation); err != nil {
+		os.Remove(infoLocation)
+		os.Remove(trashLocation)
+		return "", fmt.Errorf("while moving %s to trash: %w", path, err)
+	}
+
+	return trashLocation, nil
+}
+
+func (xdgTrash) Restore(originalPath string, trashLocation string) error {
+	_, infoDir := xdgTrashDirs()
+	infoLocation := filepath.Join(infoDir, filepath.Base(trashLocation)+".trashinfo")
+
+	if err := os.Rename(trashLocation, originalPath); err != nil {
+		return fmt.Errorf("while restoring %s from trash: %w", originalPath, err)
+	}
+
+	os.Remove(infoLocation)
+
+	return nil
+}
@@ -0,0 +1,9 @@
+package doppelparser
+
+// Collision is a single hash and the filenames doppel reported as sharing
+// it, as produced one at a time by ParseDoppelStream.
+type Collision struct {
+	Hash      string
+	Algo      HashAlgo
+	Filenames []string
+}
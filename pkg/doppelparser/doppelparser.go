@@ -1,19 +1,47 @@
 package doppelparser
 
 import (
+	"context"
+	"encoding/hex"
 	"fmt"
 	"io"
 	"iter"
-	"regexp"
+	"os"
 	"strings"
 )
 
-var hashRegex = regexp.MustCompile(`^\w+\s*\n`)
-var filenameRegex = regexp.MustCompile(`^\s+.*\n?`)
-
+// DoppelCollisions holds every hash collision parsed from a doppel output,
+// along with the algorithm each hash was computed with, when it could be
+// determined.
 type DoppelCollisions struct {
 	collisionMap map[string][]string
 	hashOrder    []string
+	algoMap      map[string]HashAlgo
+}
+
+// NewDoppelCollisions returns an empty DoppelCollisions, ready to be filled
+// one Collision at a time with Append. This is what ParseDoppelStream's
+// consumers build up incrementally instead of waiting on ParseDoppelFile.
+func NewDoppelCollisions() *DoppelCollisions {
+	return &DoppelCollisions{
+		collisionMap: make(map[string][]string),
+		hashOrder:    make([]string, 0),
+		algoMap:      make(map[string]HashAlgo),
+	}
+}
+
+// Append records a single collision. It returns an error, without modifying
+// dc, if the collision's hash was already recorded before.
+func (dc *DoppelCollisions) Append(c *Collision) error {
+	if _, ok := dc.collisionMap[c.Hash]; ok {
+		return fmt.Errorf("hash %s has already appeared before", c.Hash)
+	}
+
+	dc.hashOrder = append(dc.hashOrder, c.Hash)
+	dc.collisionMap[c.Hash] = c.Filenames
+	dc.algoMap[c.Hash] = c.Algo
+
+	return nil
 }
 
 func (dc *DoppelCollisions) Iter() iter.Seq2[string, []string] {
@@ -52,76 +80,108 @@ func (dc *DoppelCollisions) GetFilenames(hash string) ([]string, error) {
 	return result, nil
 }
 
-func lineCol(text string, index int) (int, int) {
-	line := 1
-	col := 1
-	for i := 0; i < index; i++ {
-		if text[i] == '\n' {
-			line++
-			col = 1
-		} else {
-			col++
-		}
+// HashAlgo returns the algorithm a given hash was recorded with, either from
+// a per-collision prefix or from the file's header line. ok is false if hash
+// is not a registered collision or if its algorithm could not be determined.
+func (dc *DoppelCollisions) HashAlgo(hash string) (algo HashAlgo, ok bool) {
+	if _, registered := dc.collisionMap[hash]; !registered {
+		return HashAlgoUnknown, false
 	}
 
-	return line, col
+	algo, ok = dc.algoMap[hash]
+	return algo, ok && algo != HashAlgoUnknown
 }
 
-func parseDoppelCollisions(text string) (*DoppelCollisions, error) {
-	result := &DoppelCollisions{
-		collisionMap: make(map[string][]string),
-		hashOrder:    make([]string, 0),
-	}
-	originalText := text
+// VerifyResult is the outcome of recomputing the digest of a single file
+// that was recorded under a given hash.
+type VerifyResult struct {
+	// Filename is the file that was re-hashed.
+	Filename string
 
-	currentIndex := 0
+	// Match is true if the recomputed digest still matches hash.
+	Match bool
 
-	for text != "" {
-		loc := hashRegex.FindStringIndex(text)
-		if loc == nil {
-			line, col := lineCol(originalText, currentIndex)
-			return result, fmt.Errorf("parsing error at line %d, col %d: expected hash", line, col)
-		}
+	// Err is set if the file could not be read or re-hashed. When non-nil,
+	// Match is meaningless.
+	Err error
+}
+
+// Verify recomputes the digest of every file recorded under hash, using the
+// algorithm that was parsed alongside it, and reports which ones still
+// match. If hash's algorithm couldn't be determined from the report itself
+// (no algo: header, no per-hash prefix), fallback is used instead; pass
+// HashAlgoUnknown to keep erroring out in that case. It streams files one
+// at a time rather than loading them into memory, and stops early if ctx
+// is cancelled.
+func (dc *DoppelCollisions) Verify(ctx context.Context, hash string, fallback HashAlgo) ([]VerifyResult, error) {
+	filenames, err := dc.GetFilenames(hash)
+	if err != nil {
+		return nil, err
+	}
 
-		hash := strings.TrimSpace(text[loc[0]:loc[1]])
-		text = text[loc[1]:]
+	algo, ok := dc.HashAlgo(hash)
+	if !ok || algo == HashAlgoUnknown {
+		algo = fallback
+	}
+	if algo == HashAlgoUnknown {
+		return nil, fmt.Errorf("cannot verify hash %s: unknown hash algorithm", hash)
+	}
 
-		result.hashOrder = append(result.hashOrder, hash)
-		collisions, ok := result.collisionMap[hash]
-		if ok {
-			line, col := lineCol(originalText, currentIndex)
-			return result, fmt.Errorf("parsing error at line %d, col %d: hash %s has already appeared before", line, col, hash)
+	results := make([]VerifyResult, 0, len(filenames))
+	for _, filename := range filenames {
+		if err := ctx.Err(); err != nil {
+			return results, err
 		}
 
-		currentIndex += loc[1]
+		results = append(results, verifyFile(algo, hash, filename))
+	}
 
-		for {
-			loc = filenameRegex.FindStringIndex(text)
-			if loc == nil {
-				break
-			}
+	return results, nil
+}
 
-			collisions = append(collisions, strings.TrimSpace(text[loc[0]:loc[1]]))
-			text = text[loc[1]:]
+func verifyFile(algo HashAlgo, hash string, filename string) VerifyResult {
+	result := VerifyResult{Filename: filename}
 
-			currentIndex += loc[1]
-		}
+	h, err := algo.New()
+	if err != nil {
+		result.Err = err
+		return result
+	}
 
-		result.collisionMap[hash] = collisions
+	f, err := os.Open(filename)
+	if err != nil {
+		result.Err = err
+		return result
 	}
+	defer f.Close()
 
-	return result, nil
+	if _, err := io.Copy(h, f); err != nil {
+		result.Err = err
+		return result
+	}
+
+	result.Match = hex.EncodeToString(h.Sum(nil)) == strings.ToLower(hash)
+
+	return result
 }
 
 // ParseDoppelFile takes the content of a file in the format outputed by doppel
-// and gives a parsed structure.
+// and gives a parsed structure. It is a thin, eager wrapper around
+// ParseDoppelStream, kept for callers that don't care about incremental
+// parsing.
 func ParseDoppelFile(r io.Reader) (*DoppelCollisions, error) {
-	text, err := io.ReadAll(r)
-	if err != nil {
-		return nil, fmt.Errorf("while reading input: %w", err)
+	collisions, errCh := ParseDoppelStream(r)
+
+	dc := NewDoppelCollisions()
+	for c := range collisions {
+		if err := dc.Append(c); err != nil {
+			return dc, err
+		}
 	}
 
-	result, err := parseDoppelCollisions(string(text))
+	if err := <-errCh; err != nil {
+		return dc, err
+	}
 
-	return result, err
+	return dc, nil
 }
@@ -0,0 +1,85 @@
+package doppelparser
+
+import (
+	"crypto/md5"
+	"crypto/sha1"
+	"crypto/sha256"
+	"fmt"
+	"hash"
+	"strings"
+
+	"github.com/cespare/xxhash/v2"
+	"lukechampine.com/blake3"
+)
+
+// HashAlgo identifies one of the digest algorithms doppel is known to emit.
+type HashAlgo int
+
+const (
+	// HashAlgoUnknown means the algorithm could not be determined from the input.
+	HashAlgoUnknown HashAlgo = iota
+	HashAlgoMD5
+	HashAlgoSHA1
+	HashAlgoSHA256
+	HashAlgoBLAKE3
+	HashAlgoXXHash
+)
+
+// String gives the canonical, lowercase name of the algorithm, as used in
+// both the doppel input format and the --verify-algo flag.
+func (h HashAlgo) String() string {
+	switch h {
+	case HashAlgoMD5:
+		return "md5"
+	case HashAlgoSHA1:
+		return "sha1"
+	case HashAlgoSHA256:
+		return "sha256"
+	case HashAlgoBLAKE3:
+		return "blake3"
+	case HashAlgoXXHash:
+		return "xxhash"
+	default:
+		return "unknown"
+	}
+}
+
+// New returns a fresh hash.Hash implementing the algorithm, or an error if
+// the algorithm is HashAlgoUnknown or otherwise unsupported.
+func (h HashAlgo) New() (hash.Hash, error) {
+	switch h {
+	case HashAlgoMD5:
+		return md5.New(), nil
+	case HashAlgoSHA1:
+		return sha1.New(), nil
+	case HashAlgoSHA256:
+		return sha256.New(), nil
+	case HashAlgoBLAKE3:
+		return blake3.New(32, nil), nil
+	case HashAlgoXXHash:
+		return xxhash.New(), nil
+	default:
+		return nil, fmt.Errorf("unsupported hash algorithm: %s", h)
+	}
+}
+
+// ParseHashAlgo parses an algorithm name as it would appear in a doppel
+// header line or a per-collision prefix (e.g. "sha256:abcdef...").
+// Matching is case-insensitive. An unrecognized name yields HashAlgoUnknown
+// and a non-nil error.
+func ParseHashAlgo(s string) (HashAlgo, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "md5":
+		return HashAlgoMD5, nil
+	case "sha1":
+		return HashAlgoSHA1, nil
+	case "sha256":
+		return HashAlgoSHA256, nil
+	case "blake3":
+		return HashAlgoBLAKE3, nil
+	case "xxhash":
+		return HashAlgoXXHash, nil
+	default:
+		return HashAlgoUnknown, fmt.Errorf("unrecognized hash algorithm: %q", s)
+	}
+}
@@ -0,0 +1,132 @@
+package doppelparser
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// ParseDoppelStream parses the content of a doppel output incrementally,
+// emitting each Collision on the returned channel as soon as its filename
+// list is complete, rather than waiting for the whole input to be read.
+// Only the collision being parsed is held in memory, plus the set of hashes
+// seen so far (for duplicate detection), which matters for multi-GB dedup
+// reports.
+//
+// The collisions channel is closed once the input is exhausted or a
+// parsing error is hit. The error channel then receives exactly one value
+// (nil on success) before it, too, is closed.
+func ParseDoppelStream(r io.Reader) (<-chan *Collision, <-chan error) {
+	collisions := make(chan *Collision)
+	errs := make(chan error, 1)
+
+	go func() {
+		defer close(collisions)
+		defer close(errs)
+
+		errs <- streamParse(r, collisions)
+	}()
+
+	return collisions, errs
+}
+
+// streamParse runs the actual line-by-line state machine, sending each
+// completed Collision to out.
+func streamParse(r io.Reader, out chan<- *Collision) error {
+	reader := bufio.NewReader(r)
+
+	seen := make(map[string]bool)
+	defaultAlgo := HashAlgoUnknown
+	var current *Collision
+	var currentLine int
+	lineNum := 0
+	firstLine := true
+
+	// emit sends current, if any, once its filename list is known to be
+	// complete (the next hash line is reached, or input is exhausted). A
+	// hash with no filenames under it is a malformed report, not a
+	// zero-file collision to hand the TUI.
+	emit := func() error {
+		if current == nil {
+			return nil
+		}
+		if len(current.Filenames) == 0 {
+			return fmt.Errorf("parsing error at line %d: hash %s has no filenames", currentLine, current.Hash)
+		}
+
+		out <- current
+		current = nil
+		return nil
+	}
+
+	for {
+		line, readErr := reader.ReadString('\n')
+
+		if line != "" {
+			lineNum++
+			content := strings.TrimRight(line, "\n")
+
+			switch {
+			case firstLine && isHeaderLine(content):
+				// A leading "algo: <name>" line sets the default algorithm
+				// for every hash that doesn't specify its own prefix.
+				if algo, err := ParseHashAlgo(content[len("algo:"):]); err == nil {
+					defaultAlgo = algo
+				}
+			case content == "":
+				// Blank lines between collisions are ignored.
+			case content[0] == ' ' || content[0] == '\t':
+				if current == nil {
+					return fmt.Errorf("parsing error at line %d: filename before any hash", lineNum)
+				}
+				current.Filenames = append(current.Filenames, strings.TrimSpace(content))
+			default:
+				algo, hash := parseHashLine(content)
+				if algo == HashAlgoUnknown {
+					algo = defaultAlgo
+				}
+				if seen[hash] {
+					return fmt.Errorf("parsing error at line %d: hash %s has already appeared before", lineNum, hash)
+				}
+				seen[hash] = true
+
+				if err := emit(); err != nil {
+					return err
+				}
+				current = &Collision{Hash: hash, Algo: algo}
+				currentLine = lineNum
+			}
+
+			firstLine = false
+		}
+
+		if readErr != nil {
+			if readErr != io.EOF {
+				return fmt.Errorf("while reading input: %w", readErr)
+			}
+			break
+		}
+	}
+
+	return emit()
+}
+
+func isHeaderLine(line string) bool {
+	return len(line) >= 5 && strings.EqualFold(line[:5], "algo:")
+}
+
+// parseHashLine splits a hash line into its algorithm, if prefixed (e.g.
+// "sha256:abcdef..."), and the hash itself. The prefix is stripped whenever
+// a colon is present, even if its algorithm name isn't recognized, so an
+// unrecognized prefix never ends up folded into the hash itself. It returns
+// HashAlgoUnknown if no prefix is present (or its algorithm isn't
+// recognized), leaving the caller to fall back to a default.
+func parseHashLine(line string) (HashAlgo, string) {
+	if idx := strings.IndexByte(line, ':'); idx >= 0 {
+		algo, _ := ParseHashAlgo(line[:idx])
+		return algo, strings.TrimSpace(line[idx+1:])
+	}
+
+	return HashAlgoUnknown, strings.TrimSpace(line)
+}
@@ -0,0 +1,125 @@
+package doppelparser
+
+import (
+	"strings"
+	"testing"
+)
+
+// drainStream runs input through ParseDoppelStream to completion and
+// returns every collision emitted along with the terminal error.
+func drainStream(t *testing.T, input string) ([]*Collision, error) {
+	t.Helper()
+
+	collisions, errs := ParseDoppelStream(strings.NewReader(input))
+
+	var got []*Collision
+	for c := range collisions {
+		got = append(got, c)
+	}
+
+	return got, <-errs
+}
+
+func TestParseDoppelStream(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    []*Collision
+		wantErr bool
+	}{
+		{
+			name: "header line sets the default algorithm",
+			input: "algo: sha256\n" +
+				"deadbeef\n" +
+				"  /a/one\n" +
+				"  /a/two\n",
+			want: []*Collision{
+				{Hash: "deadbeef", Algo: HashAlgoSHA256, Filenames: []string{"/a/one", "/a/two"}},
+			},
+		},
+		{
+			name: "per-collision prefix overrides the default algorithm",
+			input: "algo: md5\n" +
+				"sha256:deadbeef\n" +
+				"  /a/one\n" +
+				"  /a/two\n" +
+				"cafebabe\n" +
+				"  /b/one\n" +
+				"  /b/two\n",
+			want: []*Collision{
+				{Hash: "deadbeef", Algo: HashAlgoSHA256, Filenames: []string{"/a/one", "/a/two"}},
+				{Hash: "cafebabe", Algo: HashAlgoMD5, Filenames: []string{"/b/one", "/b/two"}},
+			},
+		},
+		{
+			name: "unrecognized prefix is still stripped from the hash",
+			input: "whirlpool:deadbeef\n" +
+				"  /a/one\n" +
+				"  /a/two\n",
+			want: []*Collision{
+				{Hash: "deadbeef", Algo: HashAlgoUnknown, Filenames: []string{"/a/one", "/a/two"}},
+			},
+		},
+		{
+			name: "duplicate hash errors",
+			input: "deadbeef\n" +
+				"  /a/one\n" +
+				"  /a/two\n" +
+				"deadbeef\n" +
+				"  /b/one\n" +
+				"  /b/two\n",
+			wantErr: true,
+		},
+		{
+			name: "no trailing newline on the last collision",
+			input: "deadbeef\n" +
+				"  /a/one\n" +
+				"  /a/two",
+			want: []*Collision{
+				{Hash: "deadbeef", Algo: HashAlgoUnknown, Filenames: []string{"/a/one", "/a/two"}},
+			},
+		},
+		{
+			name: "a hash immediately followed by another hash, with no filenames, errors",
+			input: "deadbeef\n" +
+				"cafebabe\n" +
+				"  /a/one\n" +
+				"  /a/two\n",
+			wantErr: true,
+		},
+		{
+			name: "a trailing summary line with no filenames under it errors",
+			input: "deadbeef\n" +
+				"  /a/one\n" +
+				"  /a/two\n" +
+				"Found 1 duplicate set\n",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := drainStream(t, tt.input)
+
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("expected an error, got none (collisions: %+v)", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if len(got) != len(tt.want) {
+				t.Fatalf("got %d collisions, want %d: %+v", len(got), len(tt.want), got)
+			}
+			for i, c := range got {
+				want := tt.want[i]
+				if c.Hash != want.Hash || c.Algo != want.Algo || strings.Join(c.Filenames, ",") != strings.Join(want.Filenames, ",") {
+					t.Errorf("collision %d = %+v, want %+v", i, c, want)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,84 @@
+package policy
+
+// KeepFirst keeps whichever candidate appeared first in the doppel output.
+type KeepFirst struct{}
+
+func (KeepFirst) Name() string { return "first" }
+
+func (p KeepFirst) Resolve(candidates []Candidate) ([]Candidate, []Candidate, error) {
+	return resolveByBestIndex(candidates, func(candidates []Candidate) int {
+		return 0
+	})
+}
+
+// KeepShortestPath keeps the candidate with the shortest path, on the
+// assumption that shorter paths tend to be the more canonical copy.
+type KeepShortestPath struct{}
+
+func (KeepShortestPath) Name() string { return "shortest-path" }
+
+func (p KeepShortestPath) Resolve(candidates []Candidate) ([]Candidate, []Candidate, error) {
+	return resolveByBestIndex(candidates, func(candidates []Candidate) int {
+		best := 0
+		for i, c := range candidates {
+			if len(c.Path) < len(candidates[best].Path) {
+				best = i
+			}
+		}
+
+		return best
+	})
+}
+
+// KeepOldest keeps the candidate with the earliest modification time. A
+// candidate that couldn't be stat-ed never wins the comparison: its zero
+// ModTime would otherwise look older than every real file and get kept
+// over ones that actually still exist.
+type KeepOldest struct{}
+
+func (KeepOldest) Name() string { return "oldest" }
+
+func (p KeepOldest) Resolve(candidates []Candidate) ([]Candidate, []Candidate, error) {
+	return resolveByBestIndex(candidates, func(candidates []Candidate) int {
+		best := -1
+		for i, c := range candidates {
+			if !c.Exists {
+				continue
+			}
+			if best == -1 || c.ModTime.Before(candidates[best].ModTime) {
+				best = i
+			}
+		}
+		if best == -1 {
+			return 0
+		}
+
+		return best
+	})
+}
+
+// KeepNewest keeps the candidate with the latest modification time. As with
+// KeepOldest, a candidate that couldn't be stat-ed is excluded from the
+// comparison rather than competing on its zero ModTime.
+type KeepNewest struct{}
+
+func (KeepNewest) Name() string { return "newest" }
+
+func (p KeepNewest) Resolve(candidates []Candidate) ([]Candidate, []Candidate, error) {
+	return resolveByBestIndex(candidates, func(candidates []Candidate) int {
+		best := -1
+		for i, c := range candidates {
+			if !c.Exists {
+				continue
+			}
+			if best == -1 || c.ModTime.After(candidates[best].ModTime) {
+				best = i
+			}
+		}
+		if best == -1 {
+			return 0
+		}
+
+		return best
+	})
+}
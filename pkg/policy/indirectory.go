@@ -0,0 +1,26 @@
+package policy
+
+import (
+	"path/filepath"
+)
+
+// KeepInDirectory keeps the candidate that lives in Dir, if any.
+type KeepInDirectory struct {
+	Dir string
+}
+
+func (KeepInDirectory) Name() string { return "in-directory" }
+
+func (p KeepInDirectory) Resolve(candidates []Candidate) ([]Candidate, []Candidate, error) {
+	return resolveByBestIndex(candidates, func(candidates []Candidate) int {
+		for i, c := range candidates {
+			if filepath.Dir(c.Path) == p.Dir {
+				return i
+			}
+		}
+
+		// None of them live in Dir; fall back to the first one so Resolve
+		// still makes a deterministic choice rather than erroring out.
+		return 0
+	})
+}
@@ -0,0 +1,78 @@
+// Package policy resolves a set of duplicate files down to the one to keep,
+// without requiring a human to choose, for doppelrm's non-interactive mode
+// and for the TUI's auto-select keybinding.
+package policy
+
+import (
+	"fmt"
+	"time"
+)
+
+// Candidate is one file in a collision, along with whatever metadata a
+// Policy might need to rank it.
+type Candidate struct {
+	// Path is the file's path, exactly as it appeared in the doppel output.
+	Path string
+
+	// ModTime is the file's last modification time. Meaningless when
+	// Exists is false.
+	ModTime time.Time
+
+	// Exists is whether the file could be stat-ed. A policy that ranks by
+	// ModTime must not let a missing file's zero time win a comparison.
+	Exists bool
+}
+
+// Policy decides which of a set of colliding files to keep.
+type Policy interface {
+	// Name identifies the policy, as used in the --policy flag.
+	Name() string
+
+	// Resolve splits candidates into the one file to keep and the rest to
+	// remove. candidates must not be empty.
+	Resolve(candidates []Candidate) (keep []Candidate, remove []Candidate, err error)
+}
+
+// resolveByBestIndex is the shared shape behind every policy here: pick the
+// index of the single candidate to keep, then split the rest off as remove.
+func resolveByBestIndex(candidates []Candidate, best func([]Candidate) int) ([]Candidate, []Candidate, error) {
+	if len(candidates) == 0 {
+		return nil, nil, fmt.Errorf("no candidates to resolve")
+	}
+
+	keepIndex := best(candidates)
+
+	keep := []Candidate{candidates[keepIndex]}
+	remove := make([]Candidate, 0, len(candidates)-1)
+	for i, c := range candidates {
+		if i != keepIndex {
+			remove = append(remove, c)
+		}
+	}
+
+	return keep, remove, nil
+}
+
+// New builds the named policy. dir is used by "in-directory", and patterns
+// by "prefer"; both are ignored by every other policy.
+func New(name string, dir string, patterns []string) (Policy, error) {
+	switch name {
+	case "first":
+		return KeepFirst{}, nil
+	case "shortest-path":
+		return KeepShortestPath{}, nil
+	case "oldest":
+		return KeepOldest{}, nil
+	case "newest":
+		return KeepNewest{}, nil
+	case "in-directory":
+		if dir == "" {
+			return nil, fmt.Errorf("policy %q needs a directory", name)
+		}
+		return KeepInDirectory{Dir: dir}, nil
+	case "prefer":
+		return NewPreferRegex(patterns)
+	default:
+		return nil, fmt.Errorf("unknown policy: %q", name)
+	}
+}
@@ -0,0 +1,145 @@
+package policy
+
+import (
+	"testing"
+	"time"
+)
+
+// paths returns just the Path of each candidate, for terse assertions.
+func paths(candidates []Candidate) []string {
+	out := make([]string, 0, len(candidates))
+	for _, c := range candidates {
+		out = append(out, c.Path)
+	}
+
+	return out
+}
+
+func assertResolve(t *testing.T, p Policy, candidates []Candidate, wantKeep string, wantRemove []string) {
+	t.Helper()
+
+	keep, remove, err := p.Resolve(candidates)
+	if err != nil {
+		t.Fatalf("Resolve: unexpected error: %v", err)
+	}
+
+	if len(keep) != 1 || keep[0].Path != wantKeep {
+		t.Errorf("keep = %v, want [%s]", paths(keep), wantKeep)
+	}
+
+	got := paths(remove)
+	if len(got) != len(wantRemove) {
+		t.Fatalf("remove = %v, want %v", got, wantRemove)
+	}
+	for i, g := range got {
+		if g != wantRemove[i] {
+			t.Errorf("remove[%d] = %s, want %s", i, g, wantRemove[i])
+		}
+	}
+}
+
+func TestKeepFirst(t *testing.T) {
+	candidates := []Candidate{{Path: "/a"}, {Path: "/b"}, {Path: "/c"}}
+	assertResolve(t, KeepFirst{}, candidates, "/a", []string{"/b", "/c"})
+}
+
+func TestKeepShortestPath(t *testing.T) {
+	candidates := []Candidate{{Path: "/long/path/file"}, {Path: "/short"}, {Path: "/mid/file"}}
+	assertResolve(t, KeepShortestPath{}, candidates, "/short", []string{"/long/path/file", "/mid/file"})
+}
+
+func TestKeepOldest(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+
+	t.Run("picks the earliest real mtime", func(t *testing.T) {
+		candidates := []Candidate{
+			{Path: "/newer", ModTime: now, Exists: true},
+			{Path: "/older", ModTime: now.Add(-time.Hour), Exists: true},
+		}
+		assertResolve(t, KeepOldest{}, candidates, "/older", []string{"/newer"})
+	})
+
+	t.Run("a missing file's zero ModTime never wins", func(t *testing.T) {
+		candidates := []Candidate{
+			{Path: "/missing", Exists: false},
+			{Path: "/present", ModTime: now, Exists: true},
+		}
+		assertResolve(t, KeepOldest{}, candidates, "/present", []string{"/missing"})
+	})
+}
+
+func TestKeepNewest(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+
+	t.Run("picks the latest real mtime", func(t *testing.T) {
+		candidates := []Candidate{
+			{Path: "/newer", ModTime: now, Exists: true},
+			{Path: "/older", ModTime: now.Add(-time.Hour), Exists: true},
+		}
+		assertResolve(t, KeepNewest{}, candidates, "/newer", []string{"/older"})
+	})
+
+	t.Run("a missing file is excluded from the comparison", func(t *testing.T) {
+		candidates := []Candidate{
+			{Path: "/missing", Exists: false},
+			{Path: "/present", ModTime: now, Exists: true},
+		}
+		assertResolve(t, KeepNewest{}, candidates, "/present", []string{"/missing"})
+	})
+}
+
+func TestKeepInDirectory(t *testing.T) {
+	p := KeepInDirectory{Dir: "/backup"}
+
+	t.Run("keeps the candidate in Dir", func(t *testing.T) {
+		candidates := []Candidate{{Path: "/home/file"}, {Path: "/backup/file"}}
+		assertResolve(t, p, candidates, "/backup/file", []string{"/home/file"})
+	})
+
+	t.Run("falls back to the first candidate when none match", func(t *testing.T) {
+		candidates := []Candidate{{Path: "/home/file"}, {Path: "/tmp/file"}}
+		assertResolve(t, p, candidates, "/home/file", []string{"/tmp/file"})
+	})
+}
+
+func TestPreferRegex(t *testing.T) {
+	t.Run("keeps the match from the highest-priority pattern", func(t *testing.T) {
+		p, err := NewPreferRegex([]string{`^/home/`, `^/backup/`})
+		if err != nil {
+			t.Fatalf("NewPreferRegex: %v", err)
+		}
+
+		candidates := []Candidate{{Path: "/backup/file"}, {Path: "/home/file"}, {Path: "/tmp/file"}}
+		assertResolve(t, p, candidates, "/home/file", []string{"/backup/file", "/tmp/file"})
+	})
+
+	t.Run("falls back to the first candidate when nothing matches", func(t *testing.T) {
+		p, err := NewPreferRegex([]string{`^/nope/`})
+		if err != nil {
+			t.Fatalf("NewPreferRegex: %v", err)
+		}
+
+		candidates := []Candidate{{Path: "/a"}, {Path: "/b"}}
+		assertResolve(t, p, candidates, "/a", []string{"/b"})
+	})
+
+	t.Run("requires at least one pattern", func(t *testing.T) {
+		if _, err := NewPreferRegex(nil); err == nil {
+			t.Fatal("expected an error for no patterns, got none")
+		}
+	})
+}
+
+func TestNew(t *testing.T) {
+	t.Run("unknown policy name errors", func(t *testing.T) {
+		if _, err := New("nonexistent", "", nil); err == nil {
+			t.Fatal("expected an error for an unknown policy, got none")
+		}
+	})
+
+	t.Run("in-directory requires a directory", func(t *testing.T) {
+		if _, err := New("in-directory", "", nil); err == nil {
+			t.Fatal("expected an error for a missing directory, got none")
+		}
+	})
+}
@@ -0,0 +1,49 @@
+package policy
+
+import (
+	"fmt"
+	"regexp"
+)
+
+// PreferRegex keeps the candidate matched by the earliest pattern in
+// Patterns, a priority list evaluated in order.
+type PreferRegex struct {
+	Patterns []*regexp.Regexp
+}
+
+// NewPreferRegex compiles patterns, in priority order, into a PreferRegex
+// policy.
+func NewPreferRegex(patterns []string) (PreferRegex, error) {
+	if len(patterns) == 0 {
+		return PreferRegex{}, fmt.Errorf("policy %q needs at least one --prefer pattern", "prefer")
+	}
+
+	compiled := make([]*regexp.Regexp, 0, len(patterns))
+	for _, p := range patterns {
+		re, err := regexp.Compile(p)
+		if err != nil {
+			return PreferRegex{}, fmt.Errorf("invalid --prefer pattern %q: %w", p, err)
+		}
+
+		compiled = append(compiled, re)
+	}
+
+	return PreferRegex{Patterns: compiled}, nil
+}
+
+func (PreferRegex) Name() string { return "prefer" }
+
+func (p PreferRegex) Resolve(candidates []Candidate) ([]Candidate, []Candidate, error) {
+	return resolveByBestIndex(candidates, func(candidates []Candidate) int {
+		for _, pattern := range p.Patterns {
+			for i, c := range candidates {
+				if pattern.MatchString(c.Path) {
+					return i
+				}
+			}
+		}
+
+		// Nothing matched any pattern; fall back to the first candidate.
+		return 0
+	})
+}